@@ -0,0 +1,90 @@
+// Command devkube manages local development Kubernetes environments
+// declared in a devkube.yaml spec file.
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+
+	"github.com/go-logr/logr"
+	"github.com/go-logr/stdr"
+
+	"github.com/yashvardhan-kukreja/devkube/dev"
+)
+
+func main() {
+	if err := run(os.Args[1:]); err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+}
+
+func run(args []string) error {
+	if len(args) == 0 {
+		return fmt.Errorf("usage: devkube <up|down> -f devkube.yaml")
+	}
+
+	log := stdr.New(nil)
+	ctx := logr.NewContext(context.Background(), log)
+
+	switch args[0] {
+	case "up":
+		return runUp(ctx, args[1:])
+	case "down":
+		return runDown(ctx, args[1:])
+	default:
+		return fmt.Errorf("unknown command %q, expected up or down", args[0])
+	}
+}
+
+func runUp(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("up", flag.ExitOnError)
+	specFile := fs.String("f", "devkube.yaml", "path to the devkube spec file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	_, env, err := loadSpecAndEnvironment(*specFile)
+	if err != nil {
+		return err
+	}
+
+	// Init preloads the spec's images (wired in via Spec.Environment)
+	// before running ClusterInitializers, so their workloads can reference
+	// those images without pulling.
+	if err := env.Init(ctx); err != nil {
+		return fmt.Errorf("initializing environment: %w", err)
+	}
+
+	return nil
+}
+
+func runDown(ctx context.Context, args []string) error {
+	fs := flag.NewFlagSet("down", flag.ExitOnError)
+	specFile := fs.String("f", "devkube.yaml", "path to the devkube spec file")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	_, env, err := loadSpecAndEnvironment(*specFile)
+	if err != nil {
+		return err
+	}
+
+	return env.Destroy(ctx)
+}
+
+func loadSpecAndEnvironment(specFile string) (*dev.Spec, *dev.Environment, error) {
+	spec, err := dev.LoadSpecFromFile(specFile)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	env, err := spec.Environment()
+	if err != nil {
+		return nil, nil, fmt.Errorf("building environment: %w", err)
+	}
+	return spec, env, nil
+}
@@ -1,18 +1,12 @@
 package dev
 
 import (
-	"bytes"
 	"context"
 	"errors"
 	"fmt"
 	"io"
-	"io/ioutil"
-	"os"
 	"os/exec"
-	"path"
-	"strings"
-
-	"github.com/go-logr/logr"
+	"time"
 )
 
 type EnvironmentConfig struct {
@@ -22,6 +16,21 @@ type EnvironmentConfig struct {
 	ContainerRuntime ContainerRuntime
 	NewCluster       NewClusterFunc
 	ClusterOptions   []ClusterOption
+	// KindClusterConfig describes the kind cluster topology to create.
+	// If unset, a single control-plane node is created.
+	KindClusterConfig *KindClusterConfig
+	// Provider manages the lifecycle of the local cluster. Defaults to a
+	// KindProvider.
+	Provider ClusterProvider
+	// Images to preload into the cluster once it comes up, before
+	// ClusterInitializers run so their workloads can reference them without
+	// an image pull.
+	Images []ImageSpec
+	// ProbeTimeout bounds how long Init waits for the post-install
+	// readiness probe to converge.
+	ProbeTimeout time.Duration
+	// SkipProbe disables the post-install readiness probe entirely.
+	SkipProbe bool
 }
 
 // Apply default configuration.
@@ -32,6 +41,12 @@ func (c *EnvironmentConfig) Default() {
 	if c.NewCluster == nil {
 		c.NewCluster = NewCluster
 	}
+	if c.Provider == nil {
+		c.Provider = NewKindProvider()
+	}
+	if c.ProbeTimeout == 0 {
+		c.ProbeTimeout = DefaultProbeTimeout
+	}
 }
 
 type EnvironmentOption interface {
@@ -44,6 +59,47 @@ type ClusterInitializer interface {
 	Init(ctx context.Context, cluster *Cluster) error
 }
 
+// WithContainerRuntime overrides container runtime auto-detection.
+func WithContainerRuntime(cr ContainerRuntime) EnvironmentOption {
+	return withContainerRuntime{cr}
+}
+
+type withContainerRuntime struct {
+	cr ContainerRuntime
+}
+
+func (w withContainerRuntime) ApplyToEnvironmentConfig(c *EnvironmentConfig) {
+	c.ContainerRuntime = w.cr
+}
+
+// WithClusterInitializers appends the given ClusterInitializers to the
+// Environment's configuration.
+func WithClusterInitializers(initializers ...ClusterInitializer) EnvironmentOption {
+	return withClusterInitializers{initializers}
+}
+
+type withClusterInitializers struct {
+	initializers []ClusterInitializer
+}
+
+func (w withClusterInitializers) ApplyToEnvironmentConfig(c *EnvironmentConfig) {
+	c.ClusterInitializers = append(c.ClusterInitializers, w.initializers...)
+}
+
+// WithImages appends the given images to the set preloaded into the cluster
+// during Init, before ClusterInitializers run.
+func WithImages(images ...ImageSpec) EnvironmentOption {
+	return withImages{images}
+}
+
+type withImages struct {
+	images []ImageSpec
+}
+
+func (w withImages) ApplyToEnvironmentConfig(c *EnvironmentConfig) {
+	c.Images = append(c.Images, w.images...)
+}
+
 // Environment represents a development environment.
 type Environment struct {
 	Name string
@@ -52,6 +108,9 @@ type Environment struct {
 	WorkDir string
 	Cluster *Cluster
 	config  EnvironmentConfig
+	// runtimeInfo holds the detected container runtime capabilities,
+	// populated by setContainerRuntime during Init.
+	runtimeInfo *RuntimeInfo
 }
 
 // Creates a new development environment.
@@ -69,66 +128,44 @@ func NewEnvironment(name, workDir string, opts ...EnvironmentOption) *Environmen
 
 // Initializes the environment and prepares it for use.
 func (env *Environment) Init(ctx context.Context) error {
-	if err := env.setContainerRuntime(); err != nil {
+	if err := env.ensureProviderConfigured(ctx); err != nil {
 		return err
 	}
 
-	kindConfig := `kind: Cluster
-apiVersion: kind.x-k8s.io/v1alpha4
-`
-
-	// Workaround for https://github.com/kubernetes-sigs/kind/issues/2411
-	// For BTRFS on LUKS.
-	if _, err := os.Lstat("/dev/dm-0"); err == nil {
-		kindConfig += `nodes:
-- role: control-plane
-  extraMounts:
-    - hostPath: /dev/dm-0
-      containerPath: /dev/dm-0
-      propagation: HostToContainer
-`
-	}
-
-	if err := os.MkdirAll(env.WorkDir, os.ModePerm); err != nil {
-		return fmt.Errorf("creating workdir: %w", err)
-	}
-
-	kubeconfigPath := path.Join(env.WorkDir, "kubeconfig.yaml")
-	kindconfigPath := path.Join(env.WorkDir, "/kind.yaml")
-	if err := ioutil.WriteFile(
-		kindconfigPath, []byte(kindConfig), os.ModePerm); err != nil {
-		return fmt.Errorf("creating kind cluster config: %w", err)
+	// Needs cluster creation? Capture the known clusters before Create so
+	// we only run ClusterInitializers the first time a cluster comes up.
+	existing, err := env.config.Provider.List(ctx)
+	if err != nil {
+		return fmt.Errorf("listing existing clusters: %w", err)
 	}
-
-	// Needs cluster creation?
-	var checkOutput bytes.Buffer
-	if err := env.execKindCommand(ctx, &checkOutput, nil, "get", "clusters"); err != nil {
-		return fmt.Errorf("getting existing kind clusters: %w", err)
+	createCluster := true
+	for _, name := range existing {
+		if name == env.Name {
+			createCluster = false
+		}
 	}
 
-	// Only create cluster if it is not already there.
-	createCluster := !strings.Contains(checkOutput.String(), env.Name+"\n")
-	if createCluster {
-		// Create cluster
-		if err := env.execKindCommand(
-			ctx, os.Stdout, os.Stderr,
-			"create", "cluster",
-			"--kubeconfig="+kubeconfigPath,
-			"--name="+env.Name,
-			"--config="+kindconfigPath,
-		); err != nil {
-			return fmt.Errorf("creating kind cluster: %w", err)
-		}
+	if err := env.config.Provider.Create(ctx); err != nil {
+		return fmt.Errorf("creating cluster: %w", err)
 	}
 
 	// Create _all_ the clients
 	cluster, err := env.config.NewCluster(
-		env.WorkDir, append(env.config.ClusterOptions, WithKubeconfigPath(kubeconfigPath))...)
+		env.WorkDir, append(env.config.ClusterOptions, WithKubeconfigPath(env.config.Provider.KubeconfigPath()))...)
 	if err != nil {
 		return fmt.Errorf("creating k8s clients: %w", err)
 	}
 	env.Cluster = cluster
 
+	// Preload images before running ClusterInitializers, so an initializer
+	// that deploys a workload referencing one of them doesn't hit an image
+	// pull.
+	for _, image := range env.config.Images {
+		if err := loadImage(ctx, env, image); err != nil {
+			return fmt.Errorf("preloading image: %w", err)
+		}
+	}
+
 	// Run ClusterInitializers
 	if createCluster {
 		for _, initializer := range env.config.ClusterInitializers {
@@ -138,18 +175,26 @@ apiVersion: kind.x-k8s.io/v1alpha4
 		}
 	}
 
+	if !env.config.SkipProbe {
+		health, err := env.probeUntilReady(ctx, env.config.ProbeTimeout)
+		if err != nil {
+			return fmt.Errorf("probing cluster readiness: %w", err)
+		}
+		if !health.Ready {
+			return fmt.Errorf("cluster did not become ready within %s", env.config.ProbeTimeout)
+		}
+	}
+
 	return nil
 }
 
 // Destroy/Teardown the development environment.
 func (env *Environment) Destroy(ctx context.Context) error {
-	if err := env.execKindCommand(
-		ctx, os.Stdout, os.Stderr,
-		"delete", "cluster",
-		"--kubeconfig="+path.Join(env.WorkDir, "kubeconfig.yaml"),
-		"--name="+env.Name,
-	); err != nil {
-		return fmt.Errorf("deleting kind cluster: %w", err)
+	if err := env.ensureProviderConfigured(ctx); err != nil {
+		return err
+	}
+	if err := env.config.Provider.Delete(ctx); err != nil {
+		return fmt.Errorf("deleting cluster: %w", err)
 	}
 	return nil
 }
@@ -157,39 +202,49 @@ func (env *Environment) Destroy(ctx context.Context) error {
 // Load an image from a tar archive into the environment.
 func (env *Environment) LoadImageFromTar(
 	ctx context.Context, filePath string) error {
-	if err := env.execKindCommand(
-		ctx, os.Stdout, os.Stderr,
-		"load", "image-archive", filePath,
-		"--name="+env.Name,
-	); err != nil {
+	if err := env.ensureProviderConfigured(ctx); err != nil {
+		return err
+	}
+	if err := env.config.Provider.LoadImage(ctx, filePath); err != nil {
 		return fmt.Errorf("loading image archive: %w", err)
 	}
 	return nil
 }
 
+// RunKindCommand runs an arbitrary kind CLI command. It only works when the
+// Environment's ClusterProvider is a KindProvider, which is the default.
 func (env *Environment) RunKindCommand(
 	ctx context.Context, stdout, stderr io.Writer, args ...string) error {
-	return env.execKindCommand(ctx, stdout, stderr, args...)
+	if err := env.ensureProviderConfigured(ctx); err != nil {
+		return err
+	}
+	kindProvider, ok := env.config.Provider.(*KindProvider)
+	if !ok {
+		return fmt.Errorf("RunKindCommand requires a KindProvider, got %T", env.config.Provider)
+	}
+	return kindProvider.RunKindCommand(ctx, stdout, stderr, args...)
 }
 
-func (env *Environment) execKindCommand(
-	ctx context.Context, stdout, stderr io.Writer, args ...string) error {
-	log := logr.FromContextOrDiscard(ctx)
-	log.Info("exec: kind " + strings.Join(args, " "))
-
-	kindCmd := exec.CommandContext( //nolint:gosec
-		ctx, "kind", args...,
-	)
-	kindCmd.Env = os.Environ()
-	if env.config.ContainerRuntime == "podman" {
-		kindCmd.Env = append(kindCmd.Env, "KIND_EXPERIMENTAL_PROVIDER=podman")
+// ensureProviderConfigured detects the container runtime (if not already
+// done) and configures the provider with the environment's cluster
+// identity. It is idempotent and safe to call before every provider
+// interaction, including ones (like Destroy) that may run in a process
+// that never called Init.
+func (env *Environment) ensureProviderConfigured(ctx context.Context) error {
+	if err := env.setContainerRuntime(ctx); err != nil {
+		return err
 	}
-	kindCmd.Stdout = stdout
-	kindCmd.Stderr = stderr
-	return kindCmd.Run()
+	env.config.Provider.Configure(ClusterSpec{
+		Name:              env.Name,
+		WorkDir:           env.WorkDir,
+		KindClusterConfig: env.config.KindClusterConfig,
+		ContainerRuntime:  env.config.ContainerRuntime,
+		RuntimeInfo:       env.runtimeInfo,
+	})
+	return nil
 }
 
-func (env *Environment) setContainerRuntime() error {
+func (env *Environment) setContainerRuntime(ctx context.Context) error {
 	if env.config.ContainerRuntime == ContainerRuntimeAuto {
 		cr, err := DetectContainerRuntime()
 		if err != nil {
@@ -197,6 +252,14 @@ func (env *Environment) setContainerRuntime() error {
 		}
 		env.config.ContainerRuntime = cr
 	}
+
+	if env.runtimeInfo == nil {
+		info, err := DetectRuntimeInfo(ctx, env.config.ContainerRuntime)
+		if err != nil {
+			return fmt.Errorf("detecting %s capabilities: %w", env.config.ContainerRuntime, err)
+		}
+		env.runtimeInfo = info
+	}
 	return nil
 }
 
@@ -0,0 +1,83 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+	"sigs.k8s.io/yaml"
+)
+
+// HelmInitializer renders a Helm chart with `helm template` and applies the
+// resulting manifests to the cluster. It shells out to the helm CLI rather
+// than vendoring the Helm SDK, the same way ClusterProvider implementations
+// shell out to kind/k3d/minikube.
+type HelmInitializer struct {
+	// Chart is anything `helm template` accepts: a local path, a chart
+	// repo reference (repo/chart), or an OCI reference.
+	Chart     string
+	Release   string
+	Namespace string
+	Values    map[string]interface{}
+}
+
+func (i *HelmInitializer) Init(ctx context.Context, cluster *Cluster) error {
+	args := []string{"template", i.Release, i.Chart, "--include-crds"}
+	if i.Namespace != "" {
+		// `helm template` has no --create-namespace flag (that's
+		// install/upgrade only) and never renders a Namespace object, so
+		// create it ourselves before applying the chart's manifests.
+		args = append(args, "--namespace", i.Namespace)
+		if err := applyNamespace(ctx, cluster, i.Namespace); err != nil {
+			return fmt.Errorf("creating namespace %s: %w", i.Namespace, err)
+		}
+	}
+
+	var valuesFile *os.File
+	if len(i.Values) > 0 {
+		valuesYAML, err := yaml.Marshal(i.Values)
+		if err != nil {
+			return fmt.Errorf("marshalling helm values: %w", err)
+		}
+		valuesFile, err = ioutil.TempFile("", "devkube-helm-values-*.yaml")
+		if err != nil {
+			return fmt.Errorf("creating helm values file: %w", err)
+		}
+		defer os.Remove(valuesFile.Name())
+		if _, err := valuesFile.Write(valuesYAML); err != nil {
+			return fmt.Errorf("writing helm values file: %w", err)
+		}
+		args = append(args, "--values", valuesFile.Name())
+	}
+
+	var rendered bytes.Buffer
+	cmd := exec.CommandContext(ctx, "helm", args...) //nolint:gosec
+	cmd.Stdout = &rendered
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running helm template: %w", err)
+	}
+
+	if err := applyManifests(ctx, cluster, rendered.Bytes()); err != nil {
+		return fmt.Errorf("applying helm release %s: %w", i.Release, err)
+	}
+	return nil
+}
+
+// applyNamespace server-side applies a Namespace object, so releases
+// targeting a namespace that doesn't exist yet have one to apply into.
+func applyNamespace(ctx context.Context, cluster *Cluster, name string) error {
+	ns := &unstructured.Unstructured{}
+	ns.SetAPIVersion("v1")
+	ns.SetKind("Namespace")
+	ns.SetName(name)
+	return cluster.CtrlClient.Patch(
+		ctx, ns, client.Apply,
+		client.ForceOwnership, client.FieldOwner("devkube"),
+	)
+}
@@ -0,0 +1,147 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+)
+
+// imageStreamLoader is implemented by providers that can load an image
+// archive straight from a reader, without a temporary file on disk.
+type imageStreamLoader interface {
+	LoadImageStream(ctx context.Context, r io.Reader) error
+}
+
+// daemonImageLoader is implemented by providers that can transfer an image
+// from the local container daemon into the cluster's nodes without an
+// intermediate archive file on disk.
+type daemonImageLoader interface {
+	LoadImageFromDaemon(ctx context.Context, ref string, runtime ContainerRuntime) error
+}
+
+// ociArchiveLoader is implemented by providers that can load an OCI layout
+// archive (as produced by buildah/skopeo) directly.
+type ociArchiveLoader interface {
+	LoadOCIArchive(ctx context.Context, ociDir string) error
+}
+
+// LoadImage saves the given image reference from the local container
+// daemon and loads it into the environment's cluster. It streams the
+// archive directly into the provider when possible, avoiding the disk
+// round-trip LoadImageFromTar requires.
+func (env *Environment) LoadImage(ctx context.Context, ref string) error {
+	if err := env.ensureProviderConfigured(ctx); err != nil {
+		return err
+	}
+
+	if loader, ok := env.config.Provider.(imageStreamLoader); ok {
+		saveCmd, stdout, err := env.saveCommand(ctx, ref)
+		if err != nil {
+			return err
+		}
+		if err := saveCmd.Start(); err != nil {
+			return fmt.Errorf("starting image save: %w", err)
+		}
+		if err := loader.LoadImageStream(ctx, stdout); err != nil {
+			return fmt.Errorf("streaming image into cluster: %w", err)
+		}
+		if err := saveCmd.Wait(); err != nil {
+			return fmt.Errorf("saving image: %w", err)
+		}
+		return nil
+	}
+
+	tmpFile, err := ioutil.TempFile("", "devkube-image-*.tar")
+	if err != nil {
+		return fmt.Errorf("creating temp image archive: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	saveCmd, stdout, err := env.saveCommand(ctx, ref)
+	if err != nil {
+		return err
+	}
+	if err := saveCmd.Start(); err != nil {
+		return fmt.Errorf("starting image save: %w", err)
+	}
+	if _, err := io.Copy(tmpFile, stdout); err != nil {
+		return fmt.Errorf("writing image archive: %w", err)
+	}
+	if err := saveCmd.Wait(); err != nil {
+		return fmt.Errorf("saving image: %w", err)
+	}
+
+	return env.LoadImageFromTar(ctx, tmpFile.Name())
+}
+
+// LoadImageFromDaemon transfers ref directly from the local container
+// daemon into the cluster when the provider exposes a node-native path
+// without an intermediate archive file: k3d pipes `docker/podman save` into
+// `ctr images import` inside its first server node only (other server and
+// agent nodes do not receive the image), and minikube runs `minikube image
+// load`. Falls back to LoadImage otherwise.
+func (env *Environment) LoadImageFromDaemon(ctx context.Context, ref string) error {
+	if err := env.ensureProviderConfigured(ctx); err != nil {
+		return err
+	}
+
+	if loader, ok := env.config.Provider.(daemonImageLoader); ok {
+		if err := loader.LoadImageFromDaemon(ctx, ref, env.config.ContainerRuntime); err != nil {
+			return fmt.Errorf("loading image from daemon: %w", err)
+		}
+		return nil
+	}
+	return env.LoadImage(ctx, ref)
+}
+
+// LoadOCIArchive loads an image from an OCI layout directory (as produced
+// by buildah/skopeo), as opposed to a docker-archive tarball.
+func (env *Environment) LoadOCIArchive(ctx context.Context, ociDir string) error {
+	if err := env.ensureProviderConfigured(ctx); err != nil {
+		return err
+	}
+
+	if loader, ok := env.config.Provider.(ociArchiveLoader); ok {
+		if err := loader.LoadOCIArchive(ctx, ociDir); err != nil {
+			return fmt.Errorf("loading OCI archive: %w", err)
+		}
+		return nil
+	}
+
+	tmpFile, err := ioutil.TempFile("", "devkube-oci-*.tar")
+	if err != nil {
+		return fmt.Errorf("creating temp image archive: %w", err)
+	}
+	defer os.Remove(tmpFile.Name())
+	defer tmpFile.Close()
+
+	copyCmd := exec.CommandContext( //nolint:gosec
+		ctx, "skopeo", "copy", "oci:"+ociDir, "docker-archive:"+tmpFile.Name())
+	copyCmd.Stderr = os.Stderr
+	if err := copyCmd.Run(); err != nil {
+		return fmt.Errorf("converting OCI archive via skopeo: %w", err)
+	}
+
+	return env.LoadImageFromTar(ctx, tmpFile.Name())
+}
+
+// saveCommand builds the `docker save`/`podman save` command for ref,
+// based on the detected container runtime, returning a pipe to its stdout.
+func (env *Environment) saveCommand(ctx context.Context, ref string) (*exec.Cmd, io.ReadCloser, error) {
+	binary := "docker"
+	if env.config.ContainerRuntime == ContainerRuntimePodman {
+		binary = "podman"
+	}
+
+	saveCmd := exec.CommandContext(ctx, binary, "save", ref) //nolint:gosec
+	saveCmd.Stderr = os.Stderr
+	stdout, err := saveCmd.StdoutPipe()
+	if err != nil {
+		return nil, nil, fmt.Errorf("piping %s save output: %w", binary, err)
+	}
+	return saveCmd, stdout, nil
+}
@@ -0,0 +1,184 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// K3dProvider is a ClusterProvider driving a cluster via the k3d CLI. It is
+// a lighter-weight alternative to KindProvider, useful in CI where kind's
+// dependency on a privileged docker daemon is undesirable.
+type K3dProvider struct {
+	name              string
+	kindClusterConfig *KindClusterConfig
+	kubeconfigPath    string
+}
+
+// NewK3dProvider creates a new, not yet initialized K3dProvider.
+func NewK3dProvider() *K3dProvider {
+	return &K3dProvider{}
+}
+
+// Configure sets the provider's cluster identity. It must be called before
+// any other method.
+func (p *K3dProvider) Configure(spec ClusterSpec) {
+	p.name = spec.Name
+	p.kindClusterConfig = spec.KindClusterConfig
+	p.kubeconfigPath = path.Join(spec.WorkDir, "kubeconfig.yaml")
+}
+
+func (p *K3dProvider) Create(ctx context.Context) error {
+	if err := os.MkdirAll(path.Dir(p.kubeconfigPath), os.ModePerm); err != nil {
+		return fmt.Errorf("creating workdir: %w", err)
+	}
+
+	var checkOutput bytes.Buffer
+	if err := p.execK3dCommand(ctx, &checkOutput, nil, "cluster", "list"); err != nil {
+		return fmt.Errorf("getting existing k3d clusters: %w", err)
+	}
+
+	if !strings.Contains(checkOutput.String(), p.name) {
+		servers, agents := k3dTopology(p.kindClusterConfig)
+		args := []string{
+			"cluster", "create", p.name, "--kubeconfig-update-default=false",
+			"--servers", fmt.Sprint(servers),
+			"--agents", fmt.Sprint(agents),
+		}
+		if err := p.execK3dCommand(ctx, os.Stdout, os.Stderr, args...); err != nil {
+			return fmt.Errorf("creating k3d cluster: %w", err)
+		}
+	}
+
+	var kubeconfig bytes.Buffer
+	if err := p.execK3dCommand(
+		ctx, &kubeconfig, os.Stderr,
+		"kubeconfig", "write", p.name, "--output="+p.kubeconfigPath,
+	); err != nil {
+		return fmt.Errorf("writing k3d kubeconfig: %w", err)
+	}
+	return nil
+}
+
+func (p *K3dProvider) Delete(ctx context.Context) error {
+	if err := p.execK3dCommand(
+		ctx, os.Stdout, os.Stderr,
+		"cluster", "delete", p.name,
+	); err != nil {
+		return fmt.Errorf("deleting k3d cluster: %w", err)
+	}
+	return nil
+}
+
+func (p *K3dProvider) LoadImage(ctx context.Context, archive string) error {
+	if err := p.execK3dCommand(
+		ctx, os.Stdout, os.Stderr,
+		"image", "import", archive, "--cluster", p.name,
+	); err != nil {
+		return fmt.Errorf("importing image archive: %w", err)
+	}
+	return nil
+}
+
+// LoadImageFromDaemon transfers ref straight from the local docker/podman
+// daemon into the k3d server node, piping `docker/podman save` into `ctr
+// images import` inside the node container instead of writing an
+// intermediate archive and calling `k3d image import`. Only the first
+// server node (k3d-<name>-server-0) receives the image; other server and
+// agent nodes are not loaded.
+func (p *K3dProvider) LoadImageFromDaemon(ctx context.Context, ref string, runtime ContainerRuntime) error {
+	binary := "docker"
+	if runtime == ContainerRuntimePodman {
+		binary = "podman"
+	}
+
+	saveCmd := exec.CommandContext(ctx, binary, "save", ref) //nolint:gosec
+	saveCmd.Stderr = os.Stderr
+	saveOut, err := saveCmd.StdoutPipe()
+	if err != nil {
+		return fmt.Errorf("piping %s save output: %w", binary, err)
+	}
+
+	nodeContainer := "k3d-" + p.name + "-server-0"
+	importCmd := exec.CommandContext( //nolint:gosec
+		ctx, binary, "exec", "-i", nodeContainer,
+		"ctr", "-n=k8s.io", "images", "import", "-",
+	)
+	importCmd.Stdin = saveOut
+	importCmd.Stdout = os.Stdout
+	importCmd.Stderr = os.Stderr
+
+	if err := saveCmd.Start(); err != nil {
+		return fmt.Errorf("starting %s save: %w", binary, err)
+	}
+	if err := importCmd.Run(); err != nil {
+		return fmt.Errorf("importing image into %s: %w", nodeContainer, err)
+	}
+	return saveCmd.Wait()
+}
+
+func (p *K3dProvider) List(ctx context.Context) ([]string, error) {
+	var out bytes.Buffer
+	if err := p.execK3dCommand(ctx, &out, nil, "cluster", "list", "--no-headers"); err != nil {
+		return nil, fmt.Errorf("getting existing k3d clusters: %w", err)
+	}
+	return parseK3dClusterList(out.String()), nil
+}
+
+// parseK3dClusterList parses the tabular output of `k3d cluster list
+// --no-headers`, taking the cluster name from the first whitespace-separated
+// field of each line.
+func parseK3dClusterList(output string) []string {
+	var clusters []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if fields := strings.Fields(line); len(fields) > 0 {
+			clusters = append(clusters, fields[0])
+		}
+	}
+	return clusters
+}
+
+func (p *K3dProvider) KubeconfigPath() string {
+	return p.kubeconfigPath
+}
+
+// k3dTopology translates a KindClusterConfig's node roles into the
+// --servers/--agents counts k3d expects. A nil/empty config is a single
+// control-plane node, matching kind's own default.
+func k3dTopology(cfg *KindClusterConfig) (servers, agents int) {
+	if cfg == nil || len(cfg.Nodes) == 0 {
+		return 1, 0
+	}
+	for _, node := range cfg.Nodes {
+		if node.Role == "control-plane" {
+			servers++
+		} else {
+			agents++
+		}
+	}
+	if servers == 0 {
+		servers = 1
+	}
+	return servers, agents
+}
+
+func (p *K3dProvider) execK3dCommand(
+	ctx context.Context, stdout, stderr io.Writer, args ...string) error {
+	log := logr.FromContextOrDiscard(ctx)
+	log.Info("exec: k3d " + strings.Join(args, " "))
+
+	k3dCmd := exec.CommandContext( //nolint:gosec
+		ctx, "k3d", args...,
+	)
+	k3dCmd.Env = os.Environ()
+	k3dCmd.Stdout = stdout
+	k3dCmd.Stderr = stderr
+	return k3dCmd.Run()
+}
@@ -0,0 +1,196 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// KindProvider is the default ClusterProvider, driving a cluster via the
+// kind CLI.
+type KindProvider struct {
+	name              string
+	workDir           string
+	containerRuntime  ContainerRuntime
+	runtimeInfo       *RuntimeInfo
+	kindClusterConfig *KindClusterConfig
+	kubeconfigPath    string
+}
+
+// NewKindProvider creates a new, not yet initialized KindProvider.
+func NewKindProvider() *KindProvider {
+	return &KindProvider{}
+}
+
+// Configure sets the provider's cluster identity. It must be called before
+// any other method.
+func (p *KindProvider) Configure(spec ClusterSpec) {
+	p.name = spec.Name
+	p.workDir = spec.WorkDir
+	p.containerRuntime = spec.ContainerRuntime
+	p.runtimeInfo = spec.RuntimeInfo
+	p.kindClusterConfig = spec.KindClusterConfig
+	p.kubeconfigPath = path.Join(spec.WorkDir, "kubeconfig.yaml")
+}
+
+func (p *KindProvider) Create(ctx context.Context) error {
+	kindConfigBytes, err := kindConfigYAML(p.kindClusterConfig)
+	if err != nil {
+		return err
+	}
+
+	if err := os.MkdirAll(p.workDir, os.ModePerm); err != nil {
+		return fmt.Errorf("creating workdir: %w", err)
+	}
+
+	kindconfigPath := path.Join(p.workDir, "/kind.yaml")
+	if err := ioutil.WriteFile(
+		kindconfigPath, kindConfigBytes, os.ModePerm); err != nil {
+		return fmt.Errorf("creating kind cluster config: %w", err)
+	}
+
+	// Needs cluster creation?
+	var checkOutput bytes.Buffer
+	if err := p.execKindCommand(ctx, &checkOutput, nil, "get", "clusters"); err != nil {
+		return fmt.Errorf("getting existing kind clusters: %w", err)
+	}
+
+	// Only create cluster if it is not already there.
+	if !strings.Contains(checkOutput.String(), p.name+"\n") {
+		if err := p.execKindCommand(
+			ctx, os.Stdout, os.Stderr,
+			"create", "cluster",
+			"--kubeconfig="+p.kubeconfigPath,
+			"--name="+p.name,
+			"--config="+kindconfigPath,
+		); err != nil {
+			return fmt.Errorf("creating kind cluster: %w", err)
+		}
+	}
+	return nil
+}
+
+func (p *KindProvider) Delete(ctx context.Context) error {
+	if err := p.execKindCommand(
+		ctx, os.Stdout, os.Stderr,
+		"delete", "cluster",
+		"--kubeconfig="+p.kubeconfigPath,
+		"--name="+p.name,
+	); err != nil {
+		return fmt.Errorf("deleting kind cluster: %w", err)
+	}
+	return nil
+}
+
+func (p *KindProvider) LoadImage(ctx context.Context, archive string) error {
+	if err := p.execKindCommand(
+		ctx, os.Stdout, os.Stderr,
+		"load", "image-archive", archive,
+		"--name="+p.name,
+	); err != nil {
+		return fmt.Errorf("loading image archive: %w", err)
+	}
+	return nil
+}
+
+// LoadImageStream loads an image archive read from r directly into the
+// cluster's nodes, without writing it to disk first.
+func (p *KindProvider) LoadImageStream(ctx context.Context, r io.Reader) error {
+	log := logr.FromContextOrDiscard(ctx)
+	args := []string{"load", "image-archive", "-", "--name=" + p.name}
+	log.Info("exec: kind " + strings.Join(args, " "))
+
+	kindCmd := exec.CommandContext(ctx, "kind", args...) //nolint:gosec
+	kindCmd.Env = append(os.Environ(), p.runtimeInfo.kindPodmanWorkaroundEnv()...)
+	kindCmd.Stdin = r
+	kindCmd.Stdout = os.Stdout
+	kindCmd.Stderr = os.Stderr
+	if err := kindCmd.Run(); err != nil {
+		return fmt.Errorf("streaming image archive into kind: %w", err)
+	}
+	return nil
+}
+
+func (p *KindProvider) List(ctx context.Context) ([]string, error) {
+	var out bytes.Buffer
+	if err := p.execKindCommand(ctx, &out, nil, "get", "clusters"); err != nil {
+		return nil, fmt.Errorf("getting existing kind clusters: %w", err)
+	}
+	return parseKindClusterList(out.String()), nil
+}
+
+// parseKindClusterList parses the one-cluster-name-per-line output of
+// `kind get clusters`.
+func parseKindClusterList(output string) []string {
+	var clusters []string
+	for _, line := range strings.Split(strings.TrimSpace(output), "\n") {
+		if line != "" {
+			clusters = append(clusters, line)
+		}
+	}
+	return clusters
+}
+
+func (p *KindProvider) KubeconfigPath() string {
+	return p.kubeconfigPath
+}
+
+// RunKindCommand runs an arbitrary kind CLI command against this provider's
+// cluster.
+func (p *KindProvider) RunKindCommand(
+	ctx context.Context, stdout, stderr io.Writer, args ...string) error {
+	return p.execKindCommand(ctx, stdout, stderr, args...)
+}
+
+func (p *KindProvider) execKindCommand(
+	ctx context.Context, stdout, stderr io.Writer, args ...string) error {
+	log := logr.FromContextOrDiscard(ctx)
+	log.Info("exec: kind " + strings.Join(args, " "))
+
+	kindCmd := exec.CommandContext( //nolint:gosec
+		ctx, "kind", args...,
+	)
+	kindCmd.Env = append(os.Environ(), p.runtimeInfo.kindPodmanWorkaroundEnv()...)
+	kindCmd.Stdout = stdout
+	kindCmd.Stderr = stderr
+	return kindCmd.Run()
+}
+
+// kindConfigYAML renders the kind cluster config to create, either the
+// typed KindClusterConfig provided via WithKindClusterConfig, or the
+// default single control-plane template.
+func kindConfigYAML(cfg *KindClusterConfig) ([]byte, error) {
+	if cfg != nil {
+		out, err := cfg.YAML()
+		if err != nil {
+			return nil, fmt.Errorf("rendering kind cluster config: %w", err)
+		}
+		return out, nil
+	}
+
+	kindConfig := `kind: Cluster
+apiVersion: kind.x-k8s.io/v1alpha4
+`
+
+	// Workaround for https://github.com/kubernetes-sigs/kind/issues/2411
+	// For BTRFS on LUKS.
+	if _, err := os.Lstat("/dev/dm-0"); err == nil {
+		kindConfig += `nodes:
+- role: control-plane
+  extraMounts:
+    - hostPath: /dev/dm-0
+      containerPath: /dev/dm-0
+      propagation: HostToContainer
+`
+	}
+
+	return []byte(kindConfig), nil
+}
@@ -0,0 +1,101 @@
+package dev
+
+import (
+	"fmt"
+
+	"sigs.k8s.io/yaml"
+)
+
+// KindClusterConfig is a typed representation of a kind (kind.x-k8s.io/v1alpha4)
+// cluster configuration. It allows callers to describe multi-node and
+// highly-available topologies, dual-stack networking or bring-your-own-CNI
+// setups, instead of the single control-plane template Environment.Init used
+// to hardcode.
+type KindClusterConfig struct {
+	// Nodes making up the cluster. If empty, a single control-plane node is
+	// assumed.
+	Nodes []NodeConfig `json:"nodes,omitempty"`
+	// Networking holds cluster-wide networking settings. Unset means kind's
+	// own defaults.
+	Networking *KindNetworking `json:"networking,omitempty"`
+	// FeatureGates to enable on every node's kubeadm config.
+	FeatureGates map[string]bool `json:"featureGates,omitempty"`
+	// RuntimeConfig passed through to the kube-apiserver `--runtime-config` flag.
+	RuntimeConfig map[string]string `json:"runtimeConfig,omitempty"`
+}
+
+// NodeConfig describes a single kind node.
+type NodeConfig struct {
+	// Role of the node within the cluster, e.g. "control-plane" or "worker".
+	Role string `json:"role,omitempty"`
+	// Image overrides the node image used for this node.
+	Image string `json:"image,omitempty"`
+	// ExtraMounts bind-mounted into the node container.
+	ExtraMounts []KindMount `json:"extraMounts,omitempty"`
+	// ExtraPortMappings exposed from the node container to the host.
+	ExtraPortMappings []KindPortMapping `json:"extraPortMappings,omitempty"`
+	// KubeadmConfigPatches applied to this node's kubeadm configuration.
+	KubeadmConfigPatches []string `json:"kubeadmConfigPatches,omitempty"`
+	// Labels applied to the Kubernetes Node object backing this kind node.
+	Labels map[string]string `json:"labels,omitempty"`
+}
+
+// KindMount is a host path bind-mounted into a node container.
+type KindMount struct {
+	HostPath      string `json:"hostPath"`
+	ContainerPath string `json:"containerPath"`
+	Propagation   string `json:"propagation,omitempty"`
+	Readonly      bool   `json:"readOnly,omitempty"`
+}
+
+// KindPortMapping exposes a container port on the host.
+type KindPortMapping struct {
+	ContainerPort int32  `json:"containerPort"`
+	HostPort      int32  `json:"hostPort,omitempty"`
+	ListenAddress string `json:"listenAddress,omitempty"`
+	Protocol      string `json:"protocol,omitempty"`
+}
+
+// KindNetworking holds kind's top-level networking options.
+type KindNetworking struct {
+	PodSubnet         string `json:"podSubnet,omitempty"`
+	ServiceSubnet     string `json:"serviceSubnet,omitempty"`
+	IPFamily          string `json:"ipFamily,omitempty"`
+	APIServerAddress  string `json:"apiServerAddress,omitempty"`
+	DisableDefaultCNI bool   `json:"disableDefaultCNI,omitempty"`
+}
+
+// YAML marshals the KindClusterConfig as a complete kind.x-k8s.io/v1alpha4
+// Cluster document.
+func (c KindClusterConfig) YAML() ([]byte, error) {
+	doc := struct {
+		Kind       string `json:"kind"`
+		APIVersion string `json:"apiVersion"`
+		KindClusterConfig
+	}{
+		Kind:              "Cluster",
+		APIVersion:        "kind.x-k8s.io/v1alpha4",
+		KindClusterConfig: c,
+	}
+
+	out, err := yaml.Marshal(doc)
+	if err != nil {
+		return nil, fmt.Errorf("marshalling kind cluster config: %w", err)
+	}
+	return out, nil
+}
+
+// WithKindClusterConfig configures the Environment to create its kind
+// cluster from the given typed KindClusterConfig, instead of the default
+// single control-plane template.
+func WithKindClusterConfig(c KindClusterConfig) EnvironmentOption {
+	return withKindClusterConfig{c}
+}
+
+type withKindClusterConfig struct {
+	c KindClusterConfig
+}
+
+func (w withKindClusterConfig) ApplyToEnvironmentConfig(c *EnvironmentConfig) {
+	c.KindClusterConfig = &w.c
+}
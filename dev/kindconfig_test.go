@@ -0,0 +1,36 @@
+package dev
+
+import (
+	"strings"
+	"testing"
+)
+
+func TestKindClusterConfigYAML(t *testing.T) {
+	cfg := KindClusterConfig{
+		Nodes: []NodeConfig{
+			{Role: "control-plane"},
+			{Role: "worker"},
+		},
+		Networking: &KindNetworking{
+			PodSubnet: "10.244.0.0/16",
+		},
+	}
+
+	out, err := cfg.YAML()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+
+	doc := string(out)
+	for _, want := range []string{
+		"kind: Cluster",
+		"apiVersion: kind.x-k8s.io/v1alpha4",
+		"role: control-plane",
+		"role: worker",
+		"podSubnet: 10.244.0.0/16",
+	} {
+		if !strings.Contains(doc, want) {
+			t.Errorf("expected rendered YAML to contain %q, got:\n%s", want, doc)
+		}
+	}
+}
@@ -0,0 +1,31 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+)
+
+// KustomizeInitializer renders a kustomize directory with `kubectl kustomize`
+// and applies the resulting manifests to the cluster.
+type KustomizeInitializer struct {
+	// Dir is the path to the kustomization directory.
+	Dir string
+}
+
+func (i *KustomizeInitializer) Init(ctx context.Context, cluster *Cluster) error {
+	var rendered bytes.Buffer
+	cmd := exec.CommandContext(ctx, "kubectl", "kustomize", i.Dir) //nolint:gosec
+	cmd.Stdout = &rendered
+	cmd.Stderr = os.Stderr
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("running kubectl kustomize: %w", err)
+	}
+
+	if err := applyManifests(ctx, cluster, rendered.Bytes()); err != nil {
+		return fmt.Errorf("applying kustomization %s: %w", i.Dir, err)
+	}
+	return nil
+}
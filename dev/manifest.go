@@ -0,0 +1,75 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"io/ioutil"
+	"net/http"
+	"strings"
+
+	"k8s.io/apimachinery/pkg/apis/meta/v1/unstructured"
+	"k8s.io/apimachinery/pkg/util/yaml"
+	"sigs.k8s.io/controller-runtime/pkg/client"
+)
+
+// ManifestInitializer applies a raw Kubernetes YAML manifest, read from a
+// local file path or an http(s) URL, to the cluster.
+type ManifestInitializer struct {
+	// Path is a local file path or an http(s) URL pointing at a
+	// (potentially multi-document) YAML manifest.
+	Path string
+}
+
+func (i *ManifestInitializer) Init(ctx context.Context, cluster *Cluster) error {
+	content, err := readManifestSource(i.Path)
+	if err != nil {
+		return fmt.Errorf("reading manifest %s: %w", i.Path, err)
+	}
+	if err := applyManifests(ctx, cluster, content); err != nil {
+		return fmt.Errorf("applying manifest %s: %w", i.Path, err)
+	}
+	return nil
+}
+
+func readManifestSource(path string) ([]byte, error) {
+	if strings.HasPrefix(path, "http://") || strings.HasPrefix(path, "https://") {
+		resp, err := http.Get(path) //nolint:gosec,noctx
+		if err != nil {
+			return nil, err
+		}
+		defer resp.Body.Close()
+		if resp.StatusCode != http.StatusOK {
+			return nil, fmt.Errorf("unexpected status %s", resp.Status)
+		}
+		return ioutil.ReadAll(resp.Body)
+	}
+	return ioutil.ReadFile(path)
+}
+
+// applyManifests server-side applies every YAML document in content to the
+// cluster.
+func applyManifests(ctx context.Context, cluster *Cluster, content []byte) error {
+	decoder := yaml.NewYAMLOrJSONDecoder(bytes.NewReader(content), 4096)
+	for {
+		obj := &unstructured.Unstructured{}
+		if err := decoder.Decode(obj); err != nil {
+			if errors.Is(err, io.EOF) {
+				break
+			}
+			return fmt.Errorf("decoding manifest: %w", err)
+		}
+		if len(obj.Object) == 0 {
+			continue
+		}
+		if err := cluster.CtrlClient.Patch(
+			ctx, obj, client.Apply,
+			client.ForceOwnership, client.FieldOwner("devkube"),
+		); err != nil {
+			return fmt.Errorf("applying %s %s: %w", obj.GetKind(), obj.GetName(), err)
+		}
+	}
+	return nil
+}
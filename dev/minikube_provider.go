@@ -0,0 +1,164 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"os"
+	"os/exec"
+	"path"
+	"strings"
+
+	"github.com/go-logr/logr"
+)
+
+// MinikubeProvider is a ClusterProvider driving a cluster via the minikube
+// CLI.
+type MinikubeProvider struct {
+	name           string
+	kubeconfigPath string
+}
+
+// NewMinikubeProvider creates a new, not yet initialized MinikubeProvider.
+func NewMinikubeProvider() *MinikubeProvider {
+	return &MinikubeProvider{}
+}
+
+// Configure sets the provider's cluster identity. It must be called before
+// any other method.
+func (p *MinikubeProvider) Configure(spec ClusterSpec) {
+	p.name = spec.Name
+	p.kubeconfigPath = path.Join(spec.WorkDir, "kubeconfig.yaml")
+}
+
+func (p *MinikubeProvider) Create(ctx context.Context) error {
+	if err := os.MkdirAll(path.Dir(p.kubeconfigPath), os.ModePerm); err != nil {
+		return fmt.Errorf("creating workdir: %w", err)
+	}
+
+	clusters, err := p.List(ctx)
+	if err != nil {
+		return err
+	}
+
+	exists := false
+	for _, c := range clusters {
+		if c == p.name {
+			exists = true
+		}
+	}
+	if !exists {
+		if err := p.execMinikubeCommand(
+			ctx, os.Stdout, os.Stderr,
+			"start", "--profile="+p.name, "--driver=docker",
+		); err != nil {
+			return fmt.Errorf("creating minikube cluster: %w", err)
+		}
+	}
+
+	if err := p.execMinikubeCommand(
+		ctx, nil, os.Stderr,
+		"update-context", "--profile="+p.name,
+	); err != nil {
+		return fmt.Errorf("updating minikube context: %w", err)
+	}
+
+	kubeconfigFile, err := os.Create(p.kubeconfigPath)
+	if err != nil {
+		return fmt.Errorf("creating kubeconfig file: %w", err)
+	}
+	defer kubeconfigFile.Close()
+
+	if err := p.execMinikubeCommand(
+		ctx, kubeconfigFile, os.Stderr,
+		"kubectl", "--profile="+p.name, "--", "config", "view", "--flatten",
+	); err != nil {
+		return fmt.Errorf("writing minikube kubeconfig: %w", err)
+	}
+	return nil
+}
+
+func (p *MinikubeProvider) Delete(ctx context.Context) error {
+	if err := p.execMinikubeCommand(
+		ctx, os.Stdout, os.Stderr,
+		"delete", "--profile="+p.name,
+	); err != nil {
+		return fmt.Errorf("deleting minikube cluster: %w", err)
+	}
+	return nil
+}
+
+func (p *MinikubeProvider) LoadImage(ctx context.Context, archive string) error {
+	if err := p.execMinikubeCommand(
+		ctx, os.Stdout, os.Stderr,
+		"image", "load", archive, "--profile="+p.name,
+	); err != nil {
+		return fmt.Errorf("loading image archive: %w", err)
+	}
+	return nil
+}
+
+// LoadImageFromDaemon transfers ref directly from the local container
+// daemon; `minikube image load` already resolves refs against the local
+// daemon, so this is equivalent to LoadImage.
+func (p *MinikubeProvider) LoadImageFromDaemon(ctx context.Context, ref string, runtime ContainerRuntime) error {
+	return p.LoadImage(ctx, ref)
+}
+
+// minikubeProfileList is the shape of `minikube profile list -o json`.
+type minikubeProfileList struct {
+	Valid []struct {
+		Name string `json:"Name"`
+	} `json:"valid"`
+	Invalid []struct {
+		Name string `json:"Name"`
+	} `json:"invalid"`
+}
+
+func (p *MinikubeProvider) List(ctx context.Context) ([]string, error) {
+	var out bytes.Buffer
+	if err := p.execMinikubeCommand(ctx, &out, nil, "profile", "list", "-o", "json"); err != nil {
+		return nil, fmt.Errorf("getting existing minikube profiles: %w", err)
+	}
+	return parseMinikubeProfileList(out.Bytes())
+}
+
+// parseMinikubeProfileList parses the JSON object emitted by `minikube
+// profile list -o json`, returning the names of both valid and invalid
+// profiles (invalid ones still occupy the name and must be counted so
+// Environment.Init doesn't try to recreate them under the same name).
+func parseMinikubeProfileList(data []byte) ([]string, error) {
+	var list minikubeProfileList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("parsing minikube profile list: %w", err)
+	}
+
+	var profiles []string
+	for _, profile := range list.Valid {
+		profiles = append(profiles, profile.Name)
+	}
+	for _, profile := range list.Invalid {
+		profiles = append(profiles, profile.Name)
+	}
+	return profiles, nil
+}
+
+func (p *MinikubeProvider) KubeconfigPath() string {
+	return p.kubeconfigPath
+}
+
+func (p *MinikubeProvider) execMinikubeCommand(
+	ctx context.Context, stdout, stderr io.Writer, args ...string) error {
+	log := logr.FromContextOrDiscard(ctx)
+	log.Info("exec: minikube " + strings.Join(args, " "))
+
+	minikubeCmd := exec.CommandContext( //nolint:gosec
+		ctx, "minikube", args...,
+	)
+	minikubeCmd.Env = os.Environ()
+	minikubeCmd.Stdout = stdout
+	minikubeCmd.Stderr = stderr
+	return minikubeCmd.Run()
+}
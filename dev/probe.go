@@ -0,0 +1,280 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"github.com/go-logr/logr"
+	corev1 "k8s.io/api/core/v1"
+	metav1 "k8s.io/apimachinery/pkg/apis/meta/v1"
+)
+
+const (
+	colorGreen = "\033[32m"
+	colorRed   = "\033[31m"
+	colorReset = "\033[0m"
+)
+
+// ClusterHealth is a structured report of cluster readiness, as produced by
+// Environment.Probe.
+type ClusterHealth struct {
+	// Ready is true when every node, kube-system component, the CNI and
+	// every ClusterInitializer that implements ProbingClusterInitializer
+	// reported ready.
+	Ready bool
+	Nodes []ComponentStatus
+	// KubeSystem holds the readiness of kube-system Deployments and
+	// DaemonSets.
+	KubeSystem []ComponentStatus
+	CNI        ComponentStatus
+	// Initializers holds the post-condition reported by every
+	// ProbingClusterInitializer configured on the Environment.
+	Initializers []ComponentStatus
+}
+
+// ComponentStatus reports the readiness of a single cluster component.
+type ComponentStatus struct {
+	Name    string
+	Ready   bool
+	Message string
+}
+
+// ProbingClusterInitializer is an optional extension of ClusterInitializer.
+// Initializers installing components that take time to become effective
+// (e.g. cert-manager's webhook, an OLM CatalogSource) can implement it to
+// advertise "installed and enforcing" state as part of Environment.Probe.
+type ProbingClusterInitializer interface {
+	ClusterInitializer
+	// Probe reports the post-install status of this initializer's
+	// component.
+	Probe(ctx context.Context, cluster *Cluster) (ComponentStatus, error)
+}
+
+// Probe reports the readiness of the environment's cluster: node
+// conditions, kube-system Deployment/DaemonSet readiness, CNI presence, and
+// the post-condition of every configured ProbingClusterInitializer.
+func (env *Environment) Probe(ctx context.Context) (*ClusterHealth, error) {
+	health, err := env.probeOnce(ctx)
+	if err != nil {
+		return nil, err
+	}
+	env.logProbeSummary(ctx, health)
+	return health, nil
+}
+
+// probeUntilReady polls probeOnce until the cluster reports ready or the
+// given timeout elapses, then logs the last result's summary.
+func (env *Environment) probeUntilReady(ctx context.Context, timeout time.Duration) (*ClusterHealth, error) {
+	deadline := time.Now().Add(timeout)
+	var health *ClusterHealth
+	for {
+		h, err := env.probeOnce(ctx)
+		if err != nil {
+			return nil, err
+		}
+		health = h
+		if health.Ready || time.Now().After(deadline) {
+			break
+		}
+		select {
+		case <-ctx.Done():
+			return health, ctx.Err()
+		case <-time.After(2 * time.Second):
+		}
+	}
+	env.logProbeSummary(ctx, health)
+	return health, nil
+}
+
+func (env *Environment) probeOnce(ctx context.Context) (*ClusterHealth, error) {
+	health := &ClusterHealth{Ready: true}
+
+	nodes, err := env.Cluster.Clientset.CoreV1().Nodes().List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing nodes: %w", err)
+	}
+	for _, node := range nodes.Items {
+		status := ComponentStatus{Name: node.Name, Ready: nodeReady(&node)}
+		if !status.Ready {
+			status.Message = "node not Ready"
+			health.Ready = false
+		}
+		health.Nodes = append(health.Nodes, status)
+	}
+
+	deployments, err := env.Cluster.Clientset.AppsV1().Deployments("kube-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing kube-system deployments: %w", err)
+	}
+	for _, dep := range deployments.Items {
+		wanted := int32(1)
+		if dep.Spec.Replicas != nil {
+			wanted = *dep.Spec.Replicas
+		}
+		status := ComponentStatus{Name: dep.Name, Ready: dep.Status.ReadyReplicas >= wanted}
+		if !status.Ready {
+			status.Message = fmt.Sprintf("%d/%d replicas ready", dep.Status.ReadyReplicas, wanted)
+			health.Ready = false
+		}
+		health.KubeSystem = append(health.KubeSystem, status)
+	}
+
+	daemonSets, err := env.Cluster.Clientset.AppsV1().DaemonSets("kube-system").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return nil, fmt.Errorf("listing kube-system daemonsets: %w", err)
+	}
+	for _, ds := range daemonSets.Items {
+		// CNI daemonsets are reported separately via health.CNI below,
+		// including when a BYO-CNI lives outside kube-system, so don't
+		// double-report the in-cluster default (e.g. kindnet) here too.
+		if isCNIDaemonSet(ds.Name) {
+			continue
+		}
+		status := ComponentStatus{Name: ds.Name, Ready: ds.Status.NumberReady >= ds.Status.DesiredNumberScheduled}
+		if !status.Ready {
+			status.Message = fmt.Sprintf("%d/%d pods ready", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+			health.Ready = false
+		}
+		health.KubeSystem = append(health.KubeSystem, status)
+	}
+
+	if err := env.probeCNI(ctx, health); err != nil {
+		return nil, err
+	}
+
+	for _, initializer := range env.config.ClusterInitializers {
+		probing, ok := initializer.(ProbingClusterInitializer)
+		if !ok {
+			continue
+		}
+		status, err := probing.Probe(ctx, env.Cluster)
+		if err != nil {
+			return nil, fmt.Errorf("probing initializer: %w", err)
+		}
+		if !status.Ready {
+			health.Ready = false
+		}
+		health.Initializers = append(health.Initializers, status)
+	}
+
+	return health, nil
+}
+
+func nodeReady(node *corev1.Node) bool {
+	for _, cond := range node.Status.Conditions {
+		if cond.Type == corev1.NodeReady {
+			return cond.Status == corev1.ConditionTrue
+		}
+	}
+	return false
+}
+
+// knownCNIDaemonSetNames are name fragments of CNI DaemonSets devkube
+// recognizes, whether installed by kind's default (kindnet) or by a
+// ClusterInitializer as a BYO-CNI replacement (e.g. Calico, Cilium, Flannel),
+// which may live outside kube-system. Matching is by substring, since e.g.
+// flannel's real DaemonSet is named "kube-flannel-ds", not "flannel".
+var knownCNIDaemonSetNames = []string{"kindnet", "calico-node", "cilium", "weave-net", "flannel"}
+
+func isCNIDaemonSet(name string) bool {
+	for _, known := range knownCNIDaemonSetNames {
+		if strings.Contains(name, known) {
+			return true
+		}
+	}
+	return false
+}
+
+// probeCNI reports the readiness of the cluster's CNI. Only KindProvider
+// clusters run their CNI as a DaemonSet devkube can observe directly (kindnet
+// by default, or a BYO-CNI installed by a ClusterInitializer when
+// DisableDefaultCNI is set); k3d bundles flannel into the k3s process itself,
+// and minikube's default bridge CNI has no DaemonSet either, so neither has
+// anything for this check to find. For those providers, CNI readiness is
+// left to the distro/provider and isn't gated here.
+func (env *Environment) probeCNI(ctx context.Context, health *ClusterHealth) error {
+	if _, isKind := env.config.Provider.(*KindProvider); !isKind {
+		health.CNI = ComponentStatus{Name: "cni", Ready: true, Message: "CNI managed by the cluster provider, not probed"}
+		return nil
+	}
+
+	daemonSets, err := env.Cluster.Clientset.AppsV1().DaemonSets("").List(ctx, metav1.ListOptions{})
+	if err != nil {
+		return fmt.Errorf("listing daemonsets: %w", err)
+	}
+
+	for _, ds := range daemonSets.Items {
+		if !isCNIDaemonSet(ds.Name) {
+			continue
+		}
+		health.CNI = ComponentStatus{Name: ds.Name, Ready: ds.Status.NumberReady >= ds.Status.DesiredNumberScheduled}
+		if !health.CNI.Ready {
+			health.CNI.Message = fmt.Sprintf("%d/%d pods ready", ds.Status.NumberReady, ds.Status.DesiredNumberScheduled)
+			health.Ready = false
+		}
+		return nil
+	}
+
+	if cfg := env.config.KindClusterConfig; cfg != nil && cfg.Networking != nil && cfg.Networking.DisableDefaultCNI {
+		health.CNI = ComponentStatus{Name: "cni", Ready: true, Message: "no default CNI; DisableDefaultCNI is set"}
+		return nil
+	}
+
+	health.CNI = ComponentStatus{Name: "cni", Ready: false, Message: "no known CNI daemonset found"}
+	health.Ready = false
+	return nil
+}
+
+func (env *Environment) logProbeSummary(ctx context.Context, health *ClusterHealth) {
+	log := logr.FromContextOrDiscard(ctx)
+	log.Info("cluster readiness summary")
+	for _, groups := range [][]ComponentStatus{health.Nodes, health.KubeSystem, {health.CNI}, health.Initializers} {
+		for _, status := range groups {
+			log.Info(statusLine(status))
+		}
+	}
+}
+
+// DefaultProbeTimeout is how long Environment.Init waits for the cluster to
+// report ready before giving up.
+const DefaultProbeTimeout = 2 * time.Minute
+
+// WithProbeTimeout overrides how long Environment.Init waits for the
+// post-install readiness probe to converge.
+func WithProbeTimeout(timeout time.Duration) EnvironmentOption {
+	return withProbeTimeout{timeout}
+}
+
+type withProbeTimeout struct {
+	timeout time.Duration
+}
+
+func (w withProbeTimeout) ApplyToEnvironmentConfig(c *EnvironmentConfig) {
+	c.ProbeTimeout = w.timeout
+}
+
+// WithoutProbe disables the post-install readiness probe, so Init will not
+// wait for or fail on cluster/component readiness.
+func WithoutProbe() EnvironmentOption {
+	return withoutProbe{}
+}
+
+type withoutProbe struct{}
+
+func (withoutProbe) ApplyToEnvironmentConfig(c *EnvironmentConfig) {
+	c.SkipProbe = true
+}
+
+func statusLine(status ComponentStatus) string {
+	bullet := colorGreen + "✓" + colorReset
+	if !status.Ready {
+		bullet = colorRed + "✗" + colorReset
+	}
+	line := bullet + " " + status.Name
+	if status.Message != "" {
+		line += ": " + status.Message
+	}
+	return line
+}
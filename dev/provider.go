@@ -0,0 +1,60 @@
+package dev
+
+import "context"
+
+// ClusterSpec describes the cluster an Environment wants a ClusterProvider
+// to create.
+type ClusterSpec struct {
+	// Name of the cluster.
+	Name string
+	// WorkDir of the owning Environment. Providers may use it to store
+	// kubeconfig and other state.
+	WorkDir string
+	// KindClusterConfig describes the desired node topology. Providers that
+	// do not support multi-node topologies may ignore fields they can't
+	// represent.
+	KindClusterConfig *KindClusterConfig
+	// ContainerRuntime to use when driving the underlying CLI.
+	ContainerRuntime ContainerRuntime
+	// RuntimeInfo holds the detected capabilities of ContainerRuntime, e.g.
+	// whether it is running rootless. May be nil.
+	RuntimeInfo *RuntimeInfo
+}
+
+// ClusterProvider drives the lifecycle of the local cluster backing an
+// Environment. The default is KindProvider, but Environment can be
+// configured via WithProvider to use a different backend, e.g. k3d or
+// minikube, for environments where kind is unavailable or a lighter-weight
+// backend is preferred.
+type ClusterProvider interface {
+	// Configure the provider with the cluster's identity. It must be
+	// called before any other method, including Delete/List/LoadImage on
+	// a cluster that was created in a previous process (e.g. `devkube
+	// down` without a preceding `devkube up`).
+	Configure(spec ClusterSpec)
+	// Create the cluster, or do nothing if it already exists.
+	Create(ctx context.Context) error
+	// Delete the cluster.
+	Delete(ctx context.Context) error
+	// LoadImage loads an image archive into every node of the cluster.
+	LoadImage(ctx context.Context, archive string) error
+	// List the clusters known to this provider.
+	List(ctx context.Context) ([]string, error)
+	// KubeconfigPath returns the path of the kubeconfig written for the
+	// cluster created via Create.
+	KubeconfigPath() string
+}
+
+// WithProvider configures the Environment to manage its cluster via the
+// given ClusterProvider, instead of the default KindProvider.
+func WithProvider(p ClusterProvider) EnvironmentOption {
+	return withProvider{p}
+}
+
+type withProvider struct {
+	p ClusterProvider
+}
+
+func (w withProvider) ApplyToEnvironmentConfig(c *EnvironmentConfig) {
+	c.Provider = w.p
+}
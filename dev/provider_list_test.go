@@ -0,0 +1,135 @@
+package dev
+
+import "testing"
+
+func TestParseKindClusterList(t *testing.T) {
+	cases := map[string]struct {
+		output string
+		want   []string
+	}{
+		"empty":        {output: "", want: nil},
+		"single":       {output: "kind\n", want: []string{"kind"}},
+		"multiple":     {output: "kind\nstaging\n", want: []string{"kind", "staging"}},
+		"trailing gap": {output: "kind\n\n", want: []string{"kind"}},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := parseKindClusterList(tc.output)
+			assertStringSliceEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestParseK3dClusterList(t *testing.T) {
+	cases := map[string]struct {
+		output string
+		want   []string
+	}{
+		"empty": {output: "", want: nil},
+		"single node": {
+			output: "k3d-cluster   1/1   0/0   true\n",
+			want:   []string{"k3d-cluster"},
+		},
+		"multiple clusters": {
+			output: "k3d-cluster    1/1   0/0   true\nstaging        3/3   2/2   true\n",
+			want:   []string{"k3d-cluster", "staging"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := parseK3dClusterList(tc.output)
+			assertStringSliceEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestParseMinikubeProfileList(t *testing.T) {
+	cases := map[string]struct {
+		data    string
+		want    []string
+		wantErr bool
+	}{
+		"no profiles": {
+			data: `{"valid":[],"invalid":[]}`,
+			want: nil,
+		},
+		"valid and invalid": {
+			data: `{"valid":[{"Name":"minikube"}],"invalid":[{"Name":"broken"}]}`,
+			want: []string{"minikube", "broken"},
+		},
+		"malformed": {
+			data:    `not json`,
+			wantErr: true,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got, err := parseMinikubeProfileList([]byte(tc.data))
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			assertStringSliceEqual(t, got, tc.want)
+		})
+	}
+}
+
+func TestK3dTopology(t *testing.T) {
+	cases := map[string]struct {
+		cfg         *KindClusterConfig
+		wantServers int
+		wantAgents  int
+	}{
+		"nil config defaults to single control-plane": {
+			cfg:         nil,
+			wantServers: 1,
+			wantAgents:  0,
+		},
+		"empty nodes defaults to single control-plane": {
+			cfg:         &KindClusterConfig{},
+			wantServers: 1,
+			wantAgents:  0,
+		},
+		"HA with workers": {
+			cfg: &KindClusterConfig{Nodes: []NodeConfig{
+				{Role: "control-plane"},
+				{Role: "control-plane"},
+				{Role: "control-plane"},
+				{Role: "worker"},
+				{Role: "worker"},
+			}},
+			wantServers: 3,
+			wantAgents:  2,
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			servers, agents := k3dTopology(tc.cfg)
+			if servers != tc.wantServers || agents != tc.wantAgents {
+				t.Errorf("got servers=%d agents=%d, want servers=%d agents=%d",
+					servers, agents, tc.wantServers, tc.wantAgents)
+			}
+		})
+	}
+}
+
+func assertStringSliceEqual(t *testing.T, got, want []string) {
+	t.Helper()
+	if len(got) != len(want) {
+		t.Fatalf("got %v, want %v", got, want)
+	}
+	for i := range got {
+		if got[i] != want[i] {
+			t.Fatalf("got %v, want %v", got, want)
+		}
+	}
+}
@@ -0,0 +1,139 @@
+package dev
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"os"
+	"os/exec"
+	"strings"
+)
+
+// RuntimeInfo describes the capabilities of the detected container
+// runtime, beyond just which binary is on PATH.
+type RuntimeInfo struct {
+	Runtime       ContainerRuntime
+	Rootless      bool
+	CgroupVersion string
+	StorageDriver string
+	Version       string
+	// KernelSupportsUserns reports whether the host kernel has user
+	// namespaces enabled, a prerequisite for rootless containers.
+	KernelSupportsUserns bool
+}
+
+// RuntimeInfo returns the capabilities detected for the environment's
+// container runtime. It is only populated once Init (or setContainerRuntime)
+// has run.
+func (env *Environment) RuntimeInfo() *RuntimeInfo {
+	return env.runtimeInfo
+}
+
+// DetectRuntimeInfo probes the given container runtime for its rootless
+// state, cgroup version and storage driver, via `podman/docker info`.
+func DetectRuntimeInfo(ctx context.Context, runtime ContainerRuntime) (*RuntimeInfo, error) {
+	info := &RuntimeInfo{
+		Runtime:              runtime,
+		KernelSupportsUserns: kernelSupportsUserns(),
+	}
+
+	switch runtime {
+	case ContainerRuntimePodman:
+		var out bytes.Buffer
+		cmd := exec.CommandContext(ctx, "podman", "info", "--format", "json") //nolint:gosec
+		cmd.Stdout = &out
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("running podman info: %w", err)
+		}
+
+		var podmanInfo struct {
+			Host struct {
+				Security struct {
+					Rootless bool `json:"rootless"`
+				} `json:"security"`
+				CgroupVersion string `json:"cgroupVersion"`
+			} `json:"host"`
+			Store struct {
+				GraphDriverName string `json:"graphDriverName"`
+			} `json:"store"`
+			Version struct {
+				Version string `json:"Version"`
+			} `json:"version"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &podmanInfo); err != nil {
+			return nil, fmt.Errorf("parsing podman info: %w", err)
+		}
+
+		info.Rootless = podmanInfo.Host.Security.Rootless
+		info.CgroupVersion = podmanInfo.Host.CgroupVersion
+		info.StorageDriver = podmanInfo.Store.GraphDriverName
+		info.Version = podmanInfo.Version.Version
+
+		if info.Rootless && info.CgroupVersion != "v2" {
+			return nil, fmt.Errorf(
+				"rootless podman requires cgroup v2 delegation, but host is on cgroup %s; "+
+					"enable cgroup v2 (e.g. `systemctl set-default ...` or boot with systemd.unified_cgroup_hierarchy=1)",
+				info.CgroupVersion)
+		}
+
+	case ContainerRuntimeDocker:
+		var out bytes.Buffer
+		cmd := exec.CommandContext(ctx, "docker", "info", "--format", "{{json .}}") //nolint:gosec
+		cmd.Stdout = &out
+		if err := cmd.Run(); err != nil {
+			return nil, fmt.Errorf("running docker info: %w", err)
+		}
+
+		var dockerInfo struct {
+			SecurityOptions []string `json:"SecurityOptions"`
+			CgroupVersion   string   `json:"CgroupVersion"`
+			Driver          string   `json:"Driver"`
+			ServerVersion   string   `json:"ServerVersion"`
+		}
+		if err := json.Unmarshal(out.Bytes(), &dockerInfo); err != nil {
+			return nil, fmt.Errorf("parsing docker info: %w", err)
+		}
+
+		for _, opt := range dockerInfo.SecurityOptions {
+			if strings.Contains(opt, "name=rootless") {
+				info.Rootless = true
+			}
+		}
+		info.CgroupVersion = dockerInfo.CgroupVersion
+		info.StorageDriver = dockerInfo.Driver
+		info.Version = dockerInfo.ServerVersion
+
+	default:
+		return nil, fmt.Errorf("unsupported container runtime %q", runtime)
+	}
+
+	return info, nil
+}
+
+// kindPodmanWorkaroundEnv returns the extra environment variables kind
+// needs to drive a podman daemon, plus the systemd session variables a
+// rootless one needs. It returns nil for docker or a nil RuntimeInfo.
+func (info *RuntimeInfo) kindPodmanWorkaroundEnv() []string {
+	if info == nil || info.Runtime != ContainerRuntimePodman {
+		return nil
+	}
+	env := []string{"KIND_EXPERIMENTAL_PROVIDER=podman"}
+	if info.Rootless {
+		// Rootless podman needs systemd to delegate the cgroup v2
+		// controllers kind's nodes require (cpu, memory, pids) to the
+		// user session.
+		runtimeDir := fmt.Sprintf("/run/user/%d", os.Getuid())
+		env = append(env, "DBUS_SESSION_BUS_ADDRESS=unix:path="+runtimeDir+"/bus")
+	}
+	return env
+}
+
+func kernelSupportsUserns() bool {
+	data, err := ioutil.ReadFile("/proc/sys/user/max_user_namespaces")
+	if err != nil {
+		return false
+	}
+	return strings.TrimSpace(string(data)) != "0"
+}
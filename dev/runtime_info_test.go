@@ -0,0 +1,54 @@
+package dev
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestRuntimeInfoKindPodmanWorkaroundEnv(t *testing.T) {
+	cases := map[string]struct {
+		info *RuntimeInfo
+		want []string
+	}{
+		"nil info": {
+			info: nil,
+			want: nil,
+		},
+		"docker": {
+			info: &RuntimeInfo{Runtime: ContainerRuntimeDocker},
+			want: nil,
+		},
+		"rootful podman": {
+			info: &RuntimeInfo{Runtime: ContainerRuntimePodman},
+			want: []string{"KIND_EXPERIMENTAL_PROVIDER=podman"},
+		},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			got := tc.info.kindPodmanWorkaroundEnv()
+			if !reflect.DeepEqual(got, tc.want) {
+				t.Errorf("got %v, want %v", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestKindProviderExecUsesConfiguredRuntimeInfo(t *testing.T) {
+	// Environment.ensureProviderConfigured must call Configure before any
+	// other provider method (including List), so that the podman
+	// workaround env is present on the very first `kind get clusters`
+	// invocation used to decide whether to create the cluster.
+	p := NewKindProvider()
+	p.Configure(ClusterSpec{
+		Name:             "test",
+		WorkDir:          t.TempDir(),
+		ContainerRuntime: ContainerRuntimePodman,
+		RuntimeInfo:      &RuntimeInfo{Runtime: ContainerRuntimePodman},
+	})
+
+	env := p.runtimeInfo.kindPodmanWorkaroundEnv()
+	if len(env) == 0 {
+		t.Fatalf("expected podman workaround env to be set after Configure, got none")
+	}
+}
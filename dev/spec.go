@@ -0,0 +1,204 @@
+package dev
+
+import (
+	"context"
+	"fmt"
+	"io/ioutil"
+	"time"
+
+	"sigs.k8s.io/yaml"
+)
+
+// Spec is a declarative description of a devkube Environment: its cluster
+// topology, images to preload, and the initializers to bootstrap it with.
+// It is the schema for a devkube.yaml file consumed by LoadEnvironmentFromFile
+// and the `devkube up` CLI command.
+type Spec struct {
+	Name    string `json:"name"`
+	WorkDir string `json:"workDir"`
+
+	// ContainerRuntime to use. Defaults to auto-detection.
+	ContainerRuntime ContainerRuntime `json:"containerRuntime,omitempty"`
+	// Provider selects the ClusterProvider backend: "kind" (default),
+	// "k3d" or "minikube".
+	Provider string `json:"provider,omitempty"`
+
+	// KindClusterConfig describes the node topology, when Provider is
+	// "kind" or unset.
+	KindClusterConfig *KindClusterConfig `json:"kindClusterConfig,omitempty"`
+
+	// Images to preload into the cluster once it comes up.
+	Images []ImageSpec `json:"images,omitempty"`
+
+	// Initializers bootstrap the cluster once it has been created.
+	Initializers []InitializerSpec `json:"initializers,omitempty"`
+
+	// ProbeTimeout bounds how long to wait for the cluster to report
+	// ready. Accepts a Go duration string, e.g. "2m". Defaults to
+	// DefaultProbeTimeout.
+	ProbeTimeout string `json:"probeTimeout,omitempty"`
+	// SkipProbe disables the post-install readiness probe.
+	SkipProbe bool `json:"skipProbe,omitempty"`
+}
+
+// ImageSpec describes an image to preload into the cluster.
+type ImageSpec struct {
+	// Archive is a path to a docker-archive tarball. Mutually exclusive
+	// with Ref.
+	Archive string `json:"archive,omitempty"`
+	// Ref is an image reference to pull from the local container daemon.
+	// Mutually exclusive with Archive.
+	Ref string `json:"ref,omitempty"`
+}
+
+// InitializerSpec describes a single ClusterInitializer to run, via exactly
+// one of Manifest, Helm or Kustomize.
+type InitializerSpec struct {
+	// Manifest is a local file path or http(s) URL to a raw YAML manifest.
+	Manifest string `json:"manifest,omitempty"`
+	// Helm renders and applies a Helm chart.
+	Helm *HelmSpec `json:"helm,omitempty"`
+	// Kustomize is a path to a kustomization directory.
+	Kustomize string `json:"kustomize,omitempty"`
+}
+
+// HelmSpec describes a Helm release to install.
+type HelmSpec struct {
+	Chart     string                 `json:"chart"`
+	Release   string                 `json:"release"`
+	Namespace string                 `json:"namespace,omitempty"`
+	Values    map[string]interface{} `json:"values,omitempty"`
+}
+
+// LoadSpecFromFile reads a devkube.yaml (or .json) Spec from path.
+func LoadSpecFromFile(path string) (*Spec, error) {
+	raw, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("reading spec: %w", err)
+	}
+
+	var spec Spec
+	if err := yaml.UnmarshalStrict(raw, &spec); err != nil {
+		return nil, fmt.Errorf("parsing spec: %w", err)
+	}
+	return &spec, nil
+}
+
+// LoadEnvironmentFromFile reads a devkube.yaml (or .json) Spec from path
+// and builds the Environment it describes.
+func LoadEnvironmentFromFile(path string) (*Environment, error) {
+	spec, err := LoadSpecFromFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return spec.Environment()
+}
+
+// Environment builds the Environment described by the spec.
+func (s *Spec) Environment() (*Environment, error) {
+	var opts []EnvironmentOption
+
+	if len(s.ContainerRuntime) > 0 {
+		opts = append(opts, WithContainerRuntime(s.ContainerRuntime))
+	}
+
+	provider, err := s.provider()
+	if err != nil {
+		return nil, err
+	}
+	if provider != nil {
+		opts = append(opts, WithProvider(provider))
+	}
+
+	if s.KindClusterConfig != nil {
+		opts = append(opts, WithKindClusterConfig(*s.KindClusterConfig))
+	}
+
+	if len(s.Images) > 0 {
+		opts = append(opts, WithImages(s.Images...))
+	}
+
+	if s.SkipProbe {
+		opts = append(opts, WithoutProbe())
+	} else if s.ProbeTimeout != "" {
+		timeout, err := time.ParseDuration(s.ProbeTimeout)
+		if err != nil {
+			return nil, fmt.Errorf("parsing probeTimeout: %w", err)
+		}
+		opts = append(opts, WithProbeTimeout(timeout))
+	}
+
+	initializers, err := s.initializers()
+	if err != nil {
+		return nil, err
+	}
+	opts = append(opts, WithClusterInitializers(initializers...))
+
+	return NewEnvironment(s.Name, s.WorkDir, opts...), nil
+}
+
+// LoadImages preloads every image described by the spec into the already
+// initialized env. Environment.Init already does this for images wired in
+// via Spec.Environment, before running ClusterInitializers; this is for
+// callers loading additional images afterward.
+func (s *Spec) LoadImages(ctx context.Context, env *Environment) error {
+	for _, image := range s.Images {
+		if err := loadImage(ctx, env, image); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// loadImage loads a single ImageSpec into env via whichever of
+// Archive/Ref it sets.
+func loadImage(ctx context.Context, env *Environment, image ImageSpec) error {
+	switch {
+	case image.Archive != "":
+		if err := env.LoadImageFromTar(ctx, image.Archive); err != nil {
+			return fmt.Errorf("loading image archive %s: %w", image.Archive, err)
+		}
+	case image.Ref != "":
+		if err := env.LoadImage(ctx, image.Ref); err != nil {
+			return fmt.Errorf("loading image %s: %w", image.Ref, err)
+		}
+	default:
+		return fmt.Errorf("image entry must set one of archive or ref")
+	}
+	return nil
+}
+
+func (s *Spec) provider() (ClusterProvider, error) {
+	switch s.Provider {
+	case "", "kind":
+		return nil, nil
+	case "k3d":
+		return NewK3dProvider(), nil
+	case "minikube":
+		return NewMinikubeProvider(), nil
+	default:
+		return nil, fmt.Errorf("unknown provider %q", s.Provider)
+	}
+}
+
+func (s *Spec) initializers() ([]ClusterInitializer, error) {
+	var initializers []ClusterInitializer
+	for _, i := range s.Initializers {
+		switch {
+		case i.Manifest != "":
+			initializers = append(initializers, &ManifestInitializer{Path: i.Manifest})
+		case i.Helm != nil:
+			initializers = append(initializers, &HelmInitializer{
+				Chart:     i.Helm.Chart,
+				Release:   i.Helm.Release,
+				Namespace: i.Helm.Namespace,
+				Values:    i.Helm.Values,
+			})
+		case i.Kustomize != "":
+			initializers = append(initializers, &KustomizeInitializer{Dir: i.Kustomize})
+		default:
+			return nil, fmt.Errorf("initializer must set one of manifest, helm or kustomize")
+		}
+	}
+	return initializers, nil
+}
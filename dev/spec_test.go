@@ -0,0 +1,73 @@
+package dev
+
+import "testing"
+
+func TestSpecProvider(t *testing.T) {
+	cases := map[string]struct {
+		provider string
+		wantType string // "" means nil (the default KindProvider from EnvironmentConfig.Default)
+		wantErr  bool
+	}{
+		"default is nil": {provider: "", wantType: ""},
+		"kind is nil":    {provider: "kind", wantType: ""},
+		"k3d":            {provider: "k3d", wantType: "*dev.K3dProvider"},
+		"minikube":       {provider: "minikube", wantType: "*dev.MinikubeProvider"},
+		"unknown":        {provider: "bogus", wantErr: true},
+	}
+
+	for name, tc := range cases {
+		t.Run(name, func(t *testing.T) {
+			s := &Spec{Provider: tc.provider}
+			got, err := s.provider()
+			if tc.wantErr {
+				if err == nil {
+					t.Fatalf("expected an error, got none")
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("unexpected error: %v", err)
+			}
+			switch tc.wantType {
+			case "":
+				if got != nil {
+					t.Errorf("expected nil provider, got %T", got)
+				}
+			case "*dev.K3dProvider":
+				if _, ok := got.(*K3dProvider); !ok {
+					t.Errorf("expected *K3dProvider, got %T", got)
+				}
+			case "*dev.MinikubeProvider":
+				if _, ok := got.(*MinikubeProvider); !ok {
+					t.Errorf("expected *MinikubeProvider, got %T", got)
+				}
+			}
+		})
+	}
+}
+
+func TestSpecEnvironment(t *testing.T) {
+	s := &Spec{
+		Name:     "test-env",
+		WorkDir:  t.TempDir(),
+		Provider: "k3d",
+	}
+
+	env, err := s.Environment()
+	if err != nil {
+		t.Fatalf("unexpected error: %v", err)
+	}
+	if env.Name != "test-env" {
+		t.Errorf("got name %q, want %q", env.Name, "test-env")
+	}
+	if _, ok := env.config.Provider.(*K3dProvider); !ok {
+		t.Errorf("expected k3d provider to be wired into the environment, got %T", env.config.Provider)
+	}
+}
+
+func TestSpecEnvironmentRejectsUnknownProvider(t *testing.T) {
+	s := &Spec{Name: "test-env", WorkDir: t.TempDir(), Provider: "bogus"}
+	if _, err := s.Environment(); err == nil {
+		t.Fatalf("expected an error for an unknown provider, got none")
+	}
+}